@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"text/template"
+
+	"github.com/uzimith/athena_schema/schema"
+)
+
+// serde describes the Hive SerDe, storage clause, and MapReduce input/output
+// formats used by one of the Athena storage formats supported by
+// -format=ddl and -emit=glue-json|terraform.
+type serde struct {
+	Class        string
+	StoredAs     string
+	InputFormat  string
+	OutputFormat string
+}
+
+var storageSerdes = map[string]serde{
+	"json": {
+		Class:        "org.openx.data.jsonserde.JsonSerDe",
+		StoredAs:     "TEXTFILE",
+		InputFormat:  "org.apache.hadoop.mapred.TextInputFormat",
+		OutputFormat: "org.apache.hadoop.hive.ql.io.HiveIgnoreKeyTextOutputFormat",
+	},
+	"parquet": {
+		Class:        "org.apache.hadoop.hive.ql.io.parquet.serde.ParquetHiveSerDe",
+		StoredAs:     "PARQUET",
+		InputFormat:  "org.apache.hadoop.hive.ql.io.parquet.MapredParquetInputFormat",
+		OutputFormat: "org.apache.hadoop.hive.ql.io.parquet.MapredParquetOutputFormat",
+	},
+	"orc": {
+		Class:        "org.apache.hadoop.hive.ql.io.orc.OrcSerde",
+		StoredAs:     "ORC",
+		InputFormat:  "org.apache.hadoop.hive.ql.io.orc.OrcInputFormat",
+		OutputFormat: "org.apache.hadoop.hive.ql.io.orc.OrcOutputFormat",
+	},
+}
+
+// tableProperty is a single TBLPROPERTIES entry, kept as an ordered pair so
+// the rendered DDL is deterministic (map iteration order is not).
+type tableProperty struct {
+	Key   string
+	Value string
+}
+
+// ddlTable adds the fields the built-in DDL template needs on top of
+// schema.Table.
+type ddlTable struct {
+	schema.Table
+	SerdeClass      string
+	StoredAs        string
+	Location        string
+	TableProperties []tableProperty
+}
+
+const ddlTemplateText = `{{range $ti, $t := .Tables}}CREATE EXTERNAL TABLE IF NOT EXISTS {{$t.TableName}} (
+{{range $i, $c := $t.Columns}}  ` + "`{{$c.Name}}` {{$c.Type}}" + `{{if not (last $i $t.Columns)}},{{end}}
+{{end}})
+{{if $t.PartitionColumns}}PARTITIONED BY (
+{{range $i, $c := $t.PartitionColumns}}  ` + "`{{$c.Name}}` {{$c.Type}}" + `{{if not (last $i $t.PartitionColumns)}},{{end}}
+{{end}})
+{{end}}ROW FORMAT SERDE '{{$t.SerdeClass}}'
+STORED AS {{$t.StoredAs}}
+LOCATION '{{$t.Location}}'
+{{if $t.TableProperties}}TBLPROPERTIES (
+{{range $i, $p := $t.TableProperties}}  '{{$p.Key}}' = '{{$p.Value}}'{{if not (last $i $t.TableProperties)}},{{end}}
+{{end}})
+{{end}};
+
+{{end}}`
+
+// formatDDL renders CREATE EXTERNAL TABLE statements for g.tables without
+// requiring a user-supplied template.tpl. storageFormat selects the SerDe
+// (json, parquet, or orc); location is the S3 root that folderNamePrefix,
+// each table's folder name, and folderNameSuffix are appended to.
+func (g *Generator) formatDDL(folderNamePrefix string, folderNameSuffix string, storageFormat string, location string, properties []tableProperty) []byte {
+	s, ok := storageSerdes[storageFormat]
+	if !ok {
+		log.Fatalf("unknown -storage %q: expected json, parquet, or orc", storageFormat)
+	}
+
+	ddlTables := make([]ddlTable, len(g.tables))
+	for i, t := range g.tables {
+		ddlTables[i] = ddlTable{
+			Table:           t,
+			SerdeClass:      s.Class,
+			StoredAs:        s.StoredAs,
+			Location:        location + folderNamePrefix + t.FolderName + folderNameSuffix + "/",
+			TableProperties: properties,
+		}
+	}
+
+	tmpl, err := template.New("ddl").Funcs(tmplFuncs).Parse(ddlTemplateText)
+	if err != nil {
+		log.Fatalf("ddl template parse error: %s", err.Error())
+	}
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, struct{ Tables []ddlTable }{Tables: ddlTables})
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	return buf.Bytes()
+}
+
+// parseProperties parses a comma-separated key=value list (the -properties
+// flag) into ordered TBLPROPERTIES entries.
+func parseProperties(s string) []tableProperty {
+	if s == "" {
+		return nil
+	}
+
+	parts := strings.Split(s, ",")
+	properties := make([]tableProperty, 0, len(parts))
+	for _, part := range parts {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			log.Fatalf("invalid -properties entry %q: expected key=value", part)
+		}
+		properties = append(properties, tableProperty{Key: kv[0], Value: kv[1]})
+	}
+	return properties
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/uzimith/athena_schema/schema"
+)
+
+// glueColumn mirrors a single entry in Glue's StorageDescriptor.Columns or
+// TableInput.PartitionKeys.
+type glueColumn struct {
+	Name string `json:"Name"`
+	Type string `json:"Type"`
+}
+
+type glueSerdeInfo struct {
+	SerializationLibrary string `json:"SerializationLibrary"`
+}
+
+type glueStorageDescriptor struct {
+	Columns      []glueColumn  `json:"Columns"`
+	Location     string        `json:"Location"`
+	InputFormat  string        `json:"InputFormat"`
+	OutputFormat string        `json:"OutputFormat"`
+	SerdeInfo    glueSerdeInfo `json:"SerdeInfo"`
+}
+
+type glueTableInput struct {
+	Name              string                `json:"Name"`
+	StorageDescriptor glueStorageDescriptor `json:"StorageDescriptor"`
+	PartitionKeys     []glueColumn          `json:"PartitionKeys,omitempty"`
+	TableType         string                `json:"TableType"`
+	Parameters        map[string]string     `json:"Parameters,omitempty"`
+}
+
+// glueCreateTableInput is the shape of the AWS Glue CreateTable API request.
+type glueCreateTableInput struct {
+	DatabaseName string         `json:"DatabaseName"`
+	TableInput   glueTableInput `json:"TableInput"`
+}
+
+func columnsToGlue(columns []schema.Column) []glueColumn {
+	glueColumns := make([]glueColumn, len(columns))
+	for i, c := range columns {
+		glueColumns[i] = glueColumn{Name: c.Name, Type: c.Type}
+	}
+	return glueColumns
+}
+
+func propertiesToGlue(properties []tableProperty) map[string]string {
+	if len(properties) == 0 {
+		return nil
+	}
+	params := make(map[string]string, len(properties))
+	for _, p := range properties {
+		params[p.Key] = p.Value
+	}
+	return params
+}
+
+// formatGlueJSON renders g.tables as a JSON array of AWS Glue CreateTable
+// API request payloads, one per table.
+func (g *Generator) formatGlueJSON(databaseName string, folderNamePrefix string, folderNameSuffix string, storageFormat string, location string, properties []tableProperty) []byte {
+	s, ok := storageSerdes[storageFormat]
+	if !ok {
+		log.Fatalf("unknown -storage %q: expected json, parquet, or orc", storageFormat)
+	}
+
+	inputs := make([]glueCreateTableInput, len(g.tables))
+	for i, t := range g.tables {
+		inputs[i] = glueCreateTableInput{
+			DatabaseName: databaseName,
+			TableInput: glueTableInput{
+				Name: t.TableName,
+				StorageDescriptor: glueStorageDescriptor{
+					Columns:      columnsToGlue(t.Columns),
+					Location:     location + folderNamePrefix + t.FolderName + folderNameSuffix + "/",
+					InputFormat:  s.InputFormat,
+					OutputFormat: s.OutputFormat,
+					SerdeInfo:    glueSerdeInfo{SerializationLibrary: s.Class},
+				},
+				PartitionKeys: columnsToGlue(t.PartitionColumns),
+				TableType:     "EXTERNAL_TABLE",
+				Parameters:    propertiesToGlue(properties),
+			},
+		}
+	}
+
+	out, err := json.MarshalIndent(inputs, "", "  ")
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	return append(out, '\n')
+}
+
+// formatTerraform renders g.tables as aws_glue_catalog_table HCL resources,
+// one per table.
+func (g *Generator) formatTerraform(databaseName string, folderNamePrefix string, folderNameSuffix string, storageFormat string, location string, properties []tableProperty) []byte {
+	s, ok := storageSerdes[storageFormat]
+	if !ok {
+		log.Fatalf("unknown -storage %q: expected json, parquet, or orc", storageFormat)
+	}
+
+	var buf bytes.Buffer
+	for _, t := range g.tables {
+		fmt.Fprintf(&buf, "resource \"aws_glue_catalog_table\" %q {\n", t.TableName)
+		fmt.Fprintf(&buf, "  database_name = %q\n", databaseName)
+		fmt.Fprintf(&buf, "  name          = %q\n", t.TableName)
+		fmt.Fprintf(&buf, "  table_type    = \"EXTERNAL_TABLE\"\n")
+
+		if len(properties) > 0 {
+			buf.WriteString("\n  parameters = {\n")
+			for _, p := range properties {
+				fmt.Fprintf(&buf, "    %q = %q\n", p.Key, p.Value)
+			}
+			buf.WriteString("  }\n")
+		}
+
+		buf.WriteString("\n  storage_descriptor {\n")
+		fmt.Fprintf(&buf, "    location      = %q\n", location+folderNamePrefix+t.FolderName+folderNameSuffix+"/")
+		fmt.Fprintf(&buf, "    input_format  = %q\n", s.InputFormat)
+		fmt.Fprintf(&buf, "    output_format = %q\n", s.OutputFormat)
+		buf.WriteString("\n    ser_de_info {\n")
+		fmt.Fprintf(&buf, "      serialization_library = %q\n", s.Class)
+		buf.WriteString("    }\n")
+
+		for _, c := range t.Columns {
+			buf.WriteString("\n    column {\n")
+			fmt.Fprintf(&buf, "      name = %q\n", c.Name)
+			fmt.Fprintf(&buf, "      type = %q\n", c.Type)
+			buf.WriteString("    }\n")
+		}
+		buf.WriteString("  }\n")
+
+		for _, c := range t.PartitionColumns {
+			buf.WriteString("\n  partition_keys {\n")
+			fmt.Fprintf(&buf, "    name = %q\n", c.Name)
+			fmt.Fprintf(&buf, "    type = %q\n", c.Type)
+			buf.WriteString("  }\n")
+		}
+
+		buf.WriteString("}\n\n")
+	}
+
+	return buf.Bytes()
+}
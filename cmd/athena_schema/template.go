@@ -0,0 +1,53 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"text/template"
+)
+
+var tmplFuncs = template.FuncMap{
+	"last": func(x int, a interface{}) bool {
+		return x == reflect.ValueOf(a).Len()-1
+	},
+}
+
+// formatTemplate renders g.tables through a user-supplied template.tpl,
+// for users who want full control over the output instead of -format=ddl.
+func (g *Generator) formatTemplate(templatePath string, folderNamePrefix string, folderNameSuffix string) []byte {
+	templateFile := fmt.Sprintf("%s/template.tpl", templatePath)
+	tname := filepath.Base(templateFile)
+	tmpl, err := template.New(tname).Funcs(tmplFuncs).ParseFiles(templateFile)
+
+	if err != nil {
+		log.Fatalf("Template %v parse error: %s", templatePath, err.Error())
+	}
+
+	newbytes := bytes.NewBufferString("")
+	t := &Tmpl{
+		CmdLog:           fmt.Sprintf("athena_schema %s", strings.Join(os.Args[1:], " ")),
+		FolderNamePrefix: folderNamePrefix,
+		FolderNameSuffix: folderNameSuffix,
+		Tables:           g.tables,
+	}
+
+	err = tmpl.Execute(newbytes, t)
+
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	tplcontent, err := ioutil.ReadAll(newbytes)
+
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	return tplcontent
+}
@@ -23,6 +23,8 @@ type Post struct {
 	Tags        *[]*string `json:"tags"`
 	CreatedAt   time.Time  `json:"created_at"`
 	UpdatedAt   string     `athena:"timestamp"`
+	Date        string     `athena:"partition"`
+	Internal    string     `athena:",skip"`
 }
 
 type HttpLog struct {
@@ -0,0 +1,238 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"go/types"
+	"reflect"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// loadMode is the set of go/packages information the struct-to-Athena-type
+// conversion needs: type information and syntax for the target package.
+const loadMode = packages.NeedName | packages.NeedTypes | packages.NeedTypesInfo | packages.NeedSyntax
+
+// FromPackage loads and type-checks the Go package in dir and returns a
+// Table for each name in typeNames, in order. Table and folder names
+// default to CamelToSnake(typeName); callers can override them on the
+// returned Table values before rendering.
+//
+// Loading goes through golang.org/x/tools/go/packages rather than go/build
+// and go/importer, so it resolves types correctly for module-based projects
+// (vendored deps, replace directives, build tags) instead of only GOPATH
+// layouts.
+//
+// If any type in typeNames has unsupported fields, FromPackage keeps
+// checking the rest and returns every problem found, joined with
+// errors.Join, instead of stopping at the first one.
+func FromPackage(dir string, typeNames []string) ([]Table, error) {
+	cfg := &packages.Config{
+		Mode: loadMode,
+		Dir:  dir,
+	}
+
+	pkgs, err := packages.Load(cfg, ".")
+	if err != nil {
+		return nil, fmt.Errorf("loading package %s: %w", dir, err)
+	}
+
+	var loadErrs []error
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+		for _, e := range pkg.Errors {
+			loadErrs = append(loadErrs, e)
+		}
+	})
+	if len(loadErrs) > 0 {
+		return nil, errors.Join(loadErrs...)
+	}
+
+	if len(pkgs) == 0 || pkgs[0].Types == nil {
+		return nil, fmt.Errorf("%s: no buildable Go files", dir)
+	}
+
+	return fromTypesPackage(pkgs[0].Types, pkgs[0].Fset, typeNames)
+}
+
+// fromTypesPackage walks a type-checked package and returns a Table for
+// each name in typeNames.
+func fromTypesPackage(typesPkg *types.Package, fset *token.FileSet, typeNames []string) ([]Table, error) {
+	var errs []error
+	tables := make([]Table, 0, len(typeNames))
+	for _, typeName := range typeNames {
+		structType, ok := lookupStruct(typesPkg, typeName)
+		if !ok {
+			errs = append(errs, fmt.Errorf("not found specified name struct: %s", typeName))
+			continue
+		}
+
+		columns, partitionColumns, err := genCoulmns(structType, fset)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", typeName, err))
+			continue
+		}
+
+		opts := Options{}.withDefaults(typeName)
+		tables = append(tables, Table{
+			TableName:        opts.TableName,
+			FolderName:       opts.FolderName,
+			Columns:          columns,
+			PartitionColumns: partitionColumns,
+		})
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return tables, nil
+}
+
+// lookupStruct resolves typeName in pkg's own scope. It does not walk into
+// imported packages, so two imports that happen to define the same type
+// name can never shadow the target package's own type.
+func lookupStruct(pkg *types.Package, typeName string) (*types.Struct, bool) {
+	object, ok := pkg.Scope().Lookup(typeName).(*types.TypeName)
+	if !ok {
+		return nil, false
+	}
+
+	structType, ok := object.Type().Underlying().(*types.Struct)
+	return structType, ok
+}
+
+// genCoulmns builds the Athena columns for a struct, splitting off any
+// fields tagged `athena:"partition"` into a second slice so callers can
+// render them under PARTITIONED BY instead of the regular column list.
+// Every unsupported field is collected before returning, with a
+// file:line position from fset, rather than stopping at the first one.
+func genCoulmns(fields *types.Struct, fset *token.FileSet) ([]Column, []Column, error) {
+	var errs []error
+	columns := make([]Column, 0, fields.NumFields())
+	partitionColumns := make([]Column, 0)
+	for i := 0; i < fields.NumFields(); i++ {
+		field := fields.Field(i)
+		tags := reflect.StructTag(fields.Tag(i))
+		name := CamelToSnake(field.Name())
+
+		jsonTag, ok := tags.Lookup("json")
+		if ok {
+			jsonTags := strings.Split(jsonTag, ",")
+			name = jsonTags[0]
+		}
+
+		if athenaName, ok := tags.Lookup("athena_name"); ok {
+			name = athenaName
+		}
+
+		athenaTag, athenaOk := tags.Lookup("athena")
+		directives := parseAthenaTag(athenaTag, athenaOk)
+
+		if name == "-" || directives.skip {
+			continue
+		}
+
+		sqlType := directives.explicitType
+		if sqlType == "" {
+			sqlTypeByFieldType, ok := genSqlType(field.Type(), fset)
+			if !ok {
+				errs = append(errs, fmt.Errorf("%s: no support field type: %s", fset.Position(field.Pos()), field.Name()))
+				continue
+			}
+			sqlType = sqlTypeByFieldType
+		}
+
+		column := Column{
+			Name: name,
+			Type: sqlType,
+		}
+
+		if directives.partition {
+			if !isPrimitiveAthenaType(field.Type()) {
+				errs = append(errs, fmt.Errorf("%s: partition field %s must be a primitive type", fset.Position(field.Pos()), field.Name()))
+				continue
+			}
+			partitionColumns = append(partitionColumns, column)
+			continue
+		}
+
+		columns = append(columns, column)
+	}
+
+	if len(errs) > 0 {
+		return nil, nil, errors.Join(errs...)
+	}
+	return columns, partitionColumns, nil
+}
+
+func genSqlType(fieldType types.Type, fset *token.FileSet) (string, bool) {
+	sqlType, ok := SQLTypeMap[fieldType.String()]
+	if ok {
+		return sqlType, true
+	}
+
+	switch typeKind := fieldType.(type) {
+	case *types.Slice:
+		typeStr, ok := genSqlType(typeKind.Elem(), fset)
+		if !ok {
+			return "", false
+		}
+		return fmt.Sprintf("array<%s>", typeStr), true
+	case *types.Array:
+		typeStr, ok := genSqlType(typeKind.Elem(), fset)
+		if !ok {
+			return "", false
+		}
+		return fmt.Sprintf("array<%s>", typeStr), true
+	case *types.Struct:
+		columns, partitionColumns, err := genCoulmns(typeKind, fset)
+		if err != nil {
+			return "", false
+		}
+		columns = append(columns, partitionColumns...)
+		columnStrs := make([]string, 0, len(columns))
+		for _, column := range columns {
+			columnStrs = append(columnStrs, fmt.Sprintf("%s: %s", column.Name, column.Type))
+		}
+		return fmt.Sprintf("struct<%s>", strings.Join(columnStrs, ", ")), true
+	case *types.Map:
+		key, ok := genSqlType(typeKind.Key(), fset)
+		if !ok {
+			return "", false
+		}
+		value, ok := genSqlType(typeKind.Elem(), fset)
+		if !ok {
+			return "", false
+		}
+		return fmt.Sprintf("map<%s, %s>", key, value), true
+	case *types.Pointer:
+		typeStr, ok := genSqlType(typeKind.Elem(), fset)
+		if !ok {
+			return "", false
+		}
+		return typeStr, true
+	case *types.Named:
+		return genSqlType(fieldType.Underlying(), fset)
+	default:
+		return "", false
+	}
+}
+
+// isPrimitiveAthenaType reports whether fieldType maps to a scalar Athena
+// type, looking through pointers and named types. Structs, slices, arrays,
+// and maps are not primitive and cannot be used as partition columns.
+func isPrimitiveAthenaType(fieldType types.Type) bool {
+	switch t := fieldType.(type) {
+	case *types.Pointer:
+		return isPrimitiveAthenaType(t.Elem())
+	case *types.Named:
+		return isPrimitiveAthenaType(t.Underlying())
+	case *types.Slice, *types.Array, *types.Struct, *types.Map:
+		return false
+	default:
+		_, ok := SQLTypeMap[fieldType.String()]
+		return ok
+	}
+}
@@ -0,0 +1,146 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// FromType converts a Go struct type into a Table using reflection, for
+// callers that have a runtime type in hand (tests, go:generate-less tools)
+// rather than a package directory to type-check. t must be a struct, or a
+// pointer to one.
+func FromType(t reflect.Type, opts Options) (Table, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return Table{}, fmt.Errorf("schema: FromType: %s is not a struct", t)
+	}
+
+	columns, partitionColumns, err := genCoulmnsReflect(t)
+	if err != nil {
+		return Table{}, fmt.Errorf("%s: %w", t.Name(), err)
+	}
+
+	opts = opts.withDefaults(t.Name())
+	return Table{
+		TableName:        opts.TableName,
+		FolderName:       opts.FolderName,
+		Columns:          columns,
+		PartitionColumns: partitionColumns,
+	}, nil
+}
+
+func genCoulmnsReflect(t reflect.Type) ([]Column, []Column, error) {
+	var errs []error
+	columns := make([]Column, 0, t.NumField())
+	partitionColumns := make([]Column, 0)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := CamelToSnake(field.Name)
+
+		if jsonTag, ok := field.Tag.Lookup("json"); ok {
+			name = strings.Split(jsonTag, ",")[0]
+		}
+
+		if athenaName, ok := field.Tag.Lookup("athena_name"); ok {
+			name = athenaName
+		}
+
+		athenaTag, athenaOk := field.Tag.Lookup("athena")
+		directives := parseAthenaTag(athenaTag, athenaOk)
+
+		if name == "-" || directives.skip {
+			continue
+		}
+
+		sqlType := directives.explicitType
+		if sqlType == "" {
+			sqlTypeByFieldType, ok := genSqlTypeReflect(field.Type)
+			if !ok {
+				errs = append(errs, fmt.Errorf("no support field type: %s", field.Name))
+				continue
+			}
+			sqlType = sqlTypeByFieldType
+		}
+
+		column := Column{
+			Name: name,
+			Type: sqlType,
+		}
+
+		if directives.partition {
+			if !isPrimitiveAthenaTypeReflect(field.Type) {
+				errs = append(errs, fmt.Errorf("partition field %s must be a primitive type", field.Name))
+				continue
+			}
+			partitionColumns = append(partitionColumns, column)
+			continue
+		}
+
+		columns = append(columns, column)
+	}
+
+	if len(errs) > 0 {
+		return nil, nil, errors.Join(errs...)
+	}
+	return columns, partitionColumns, nil
+}
+
+func genSqlTypeReflect(fieldType reflect.Type) (string, bool) {
+	sqlType, ok := SQLTypeMap[fieldType.String()]
+	if ok {
+		return sqlType, true
+	}
+
+	switch fieldType.Kind() {
+	case reflect.Slice, reflect.Array:
+		typeStr, ok := genSqlTypeReflect(fieldType.Elem())
+		if !ok {
+			return "", false
+		}
+		return fmt.Sprintf("array<%s>", typeStr), true
+	case reflect.Struct:
+		columns, partitionColumns, err := genCoulmnsReflect(fieldType)
+		if err != nil {
+			return "", false
+		}
+		columns = append(columns, partitionColumns...)
+		columnStrs := make([]string, 0, len(columns))
+		for _, column := range columns {
+			columnStrs = append(columnStrs, fmt.Sprintf("%s: %s", column.Name, column.Type))
+		}
+		return fmt.Sprintf("struct<%s>", strings.Join(columnStrs, ", ")), true
+	case reflect.Map:
+		key, ok := genSqlTypeReflect(fieldType.Key())
+		if !ok {
+			return "", false
+		}
+		value, ok := genSqlTypeReflect(fieldType.Elem())
+		if !ok {
+			return "", false
+		}
+		return fmt.Sprintf("map<%s, %s>", key, value), true
+	case reflect.Ptr:
+		return genSqlTypeReflect(fieldType.Elem())
+	default:
+		return "", false
+	}
+}
+
+// isPrimitiveAthenaTypeReflect reports whether fieldType maps to a scalar
+// Athena type, looking through pointers. Structs, slices, arrays, and maps
+// are not primitive and cannot be used as partition columns.
+func isPrimitiveAthenaTypeReflect(fieldType reflect.Type) bool {
+	switch fieldType.Kind() {
+	case reflect.Ptr:
+		return isPrimitiveAthenaTypeReflect(fieldType.Elem())
+	case reflect.Slice, reflect.Array, reflect.Struct, reflect.Map:
+		return false
+	default:
+		_, ok := SQLTypeMap[fieldType.String()]
+		return ok
+	}
+}
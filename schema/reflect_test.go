@@ -0,0 +1,170 @@
+package schema
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFromType_SQLTypeMap(t *testing.T) {
+	type Primitives struct {
+		Bool   bool
+		Str    string
+		Int    int64
+		Float  float64
+		Tagged string `json:"renamed"`
+	}
+
+	table, err := FromType(reflect.TypeOf(Primitives{}), Options{})
+	if err != nil {
+		t.Fatalf("FromType: %v", err)
+	}
+
+	want := []Column{
+		{Name: "bool", Type: "boolean"},
+		{Name: "str", Type: "string"},
+		{Name: "int", Type: "int"},
+		{Name: "float", Type: "double"},
+		{Name: "renamed", Type: "string"},
+	}
+	if !reflect.DeepEqual(table.Columns, want) {
+		t.Errorf("Columns = %+v, want %+v", table.Columns, want)
+	}
+}
+
+func TestFromType_NestedStruct(t *testing.T) {
+	type Inner struct {
+		A string
+	}
+	type Outer struct {
+		Inner Inner
+	}
+
+	table, err := FromType(reflect.TypeOf(Outer{}), Options{})
+	if err != nil {
+		t.Fatalf("FromType: %v", err)
+	}
+
+	want := []Column{{Name: "inner", Type: "struct<a: string>"}}
+	if !reflect.DeepEqual(table.Columns, want) {
+		t.Errorf("Columns = %+v, want %+v", table.Columns, want)
+	}
+}
+
+func TestFromType_SliceAndMap(t *testing.T) {
+	type Post struct {
+		Tags    []string
+		Headers map[string]string
+	}
+
+	table, err := FromType(reflect.TypeOf(Post{}), Options{})
+	if err != nil {
+		t.Fatalf("FromType: %v", err)
+	}
+
+	want := []Column{
+		{Name: "tags", Type: "array<string>"},
+		{Name: "headers", Type: "map<string, string>"},
+	}
+	if !reflect.DeepEqual(table.Columns, want) {
+		t.Errorf("Columns = %+v, want %+v", table.Columns, want)
+	}
+}
+
+func TestFromType_AthenaNameOverridesJSONTag(t *testing.T) {
+	type Post struct {
+		Title string `json:"title" athena_name:"post_title"`
+	}
+
+	table, err := FromType(reflect.TypeOf(Post{}), Options{})
+	if err != nil {
+		t.Fatalf("FromType: %v", err)
+	}
+
+	if got := table.Columns[0].Name; got != "post_title" {
+		t.Errorf("Name = %q, want post_title", got)
+	}
+}
+
+func TestFromType_SkipTag(t *testing.T) {
+	type Post struct {
+		Title    string `json:"title"`
+		Internal string `athena:",skip"`
+	}
+
+	table, err := FromType(reflect.TypeOf(Post{}), Options{})
+	if err != nil {
+		t.Fatalf("FromType: %v", err)
+	}
+
+	want := []Column{{Name: "title", Type: "string"}}
+	if !reflect.DeepEqual(table.Columns, want) {
+		t.Errorf("Columns = %+v, want %+v", table.Columns, want)
+	}
+}
+
+func TestFromType_PartitionColumnsSplitFromColumns(t *testing.T) {
+	type Post struct {
+		Title string `json:"title"`
+		Date  string `athena:"partition"`
+	}
+
+	table, err := FromType(reflect.TypeOf(Post{}), Options{})
+	if err != nil {
+		t.Fatalf("FromType: %v", err)
+	}
+
+	wantColumns := []Column{{Name: "title", Type: "string"}}
+	wantPartitions := []Column{{Name: "date", Type: "string"}}
+	if !reflect.DeepEqual(table.Columns, wantColumns) {
+		t.Errorf("Columns = %+v, want %+v", table.Columns, wantColumns)
+	}
+	if !reflect.DeepEqual(table.PartitionColumns, wantPartitions) {
+		t.Errorf("PartitionColumns = %+v, want %+v", table.PartitionColumns, wantPartitions)
+	}
+}
+
+func TestFromType_PartitionMustBePrimitive(t *testing.T) {
+	type Nested struct{ A string }
+	type Post struct {
+		Title  string `json:"title"`
+		Nested Nested `athena:"partition"`
+	}
+
+	_, err := FromType(reflect.TypeOf(Post{}), Options{})
+	if err == nil {
+		t.Fatal("expected an error for a non-primitive partition field")
+	}
+}
+
+func TestFromType_AggregatesAllUnsupportedFields(t *testing.T) {
+	type Bad struct {
+		A chan int
+		B func()
+	}
+
+	_, err := FromType(reflect.TypeOf(Bad{}), Options{})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if !strings.Contains(err.Error(), "field type: A") || !strings.Contains(err.Error(), "field type: B") {
+		t.Errorf("error %q should mention both unsupported fields A and B, not just the first", err.Error())
+	}
+}
+
+func TestFromType_DefaultsTableAndFolderName(t *testing.T) {
+	type HTTPLog struct {
+		Method string
+	}
+
+	table, err := FromType(reflect.TypeOf(HTTPLog{}), Options{})
+	if err != nil {
+		t.Fatalf("FromType: %v", err)
+	}
+	if table.TableName != "http_log" {
+		t.Errorf("TableName = %q, want http_log", table.TableName)
+	}
+	if table.FolderName != table.TableName {
+		t.Errorf("FolderName = %q, want %q", table.FolderName, table.TableName)
+	}
+}
@@ -0,0 +1,220 @@
+// Package schema converts Go struct definitions into Athena table
+// descriptions. It is used both by the athena_schema command and as a
+// library for programs (tests, go:generate tools, migration scripts) that
+// want the struct-to-Athena-type mapping without shelling out.
+package schema
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Table is an Athena table derived from a single Go struct.
+type Table struct {
+	TableName  string
+	FolderName string
+	Columns    []Column
+
+	// PartitionColumns holds fields tagged `athena:"partition"`; they are
+	// rendered under PARTITIONED BY instead of the regular column list.
+	PartitionColumns []Column
+}
+
+// Column is a single Athena column derived from a struct field.
+type Column struct {
+	Name string
+	Type string
+}
+
+// Options controls how a single Go type is converted into a Table.
+type Options struct {
+	// TableName defaults to CamelToSnake(typeName) when empty.
+	TableName string
+	// FolderName defaults to TableName when empty.
+	FolderName string
+}
+
+func (o Options) withDefaults(typeName string) Options {
+	if o.TableName == "" {
+		o.TableName = CamelToSnake(typeName)
+	}
+	if o.FolderName == "" {
+		o.FolderName = o.TableName
+	}
+	return o
+}
+
+// SQLTypeMap maps the Go types it recognizes to their Athena equivalents.
+var SQLTypeMap = map[string]string{
+	"bool":      "boolean",
+	"string":    "string",
+	"int":       "int",
+	"int8":      "int",
+	"int16":     "int",
+	"int32":     "int",
+	"int64":     "int",
+	"uint8":     "int",
+	"uint16":    "int",
+	"uint32":    "int",
+	"uint64":    "int",
+	"float32":   "float",
+	"float64":   "double",
+	"[]byte":    "string",
+	"time.Time": "timestamp",
+}
+
+// CamelToSnake converts a CamelCase identifier to snake_case, keeping common
+// initialisms (ID, HTTP, URL, ...) together as a single word.
+func CamelToSnake(s string) string {
+	var result string
+	var words []string
+	var lastPos int
+	rs := []rune(s)
+
+	for i := 0; i < len(rs); i++ {
+		if i > 0 && unicode.IsUpper(rs[i]) {
+			if initialism := startsWithInitialism(s[lastPos:]); initialism != "" {
+				words = append(words, initialism)
+
+				i += len(initialism) - 1
+				lastPos = i
+				continue
+			}
+
+			words = append(words, s[lastPos:i])
+			lastPos = i
+		}
+	}
+
+	// append the last word
+	if s[lastPos:] != "" {
+		words = append(words, s[lastPos:])
+	}
+
+	for k, word := range words {
+		if k > 0 {
+			result += "_"
+		}
+
+		result += strings.ToLower(word)
+	}
+
+	return result
+}
+
+// startsWithInitialism returns the initialism if the given string begins with it
+func startsWithInitialism(s string) string {
+	var initialism string
+	// the longest initialism is 5 char, the shortest 2
+	for i := 1; i <= 5; i++ {
+		if len(s) > i-1 && commonInitialisms[s[:i]] {
+			initialism = s[:i]
+		}
+	}
+	return initialism
+}
+
+// commonInitialisms, taken from
+// https://github.com/golang/lint/blob/206c0f020eba0f7fbcfbc467a5eb808037df2ed6/lint.go#L731
+var commonInitialisms = map[string]bool{
+	"ACL":   true,
+	"API":   true,
+	"ASCII": true,
+	"CPU":   true,
+	"CSS":   true,
+	"DNS":   true,
+	"EOF":   true,
+	"ETA":   true,
+	"GPU":   true,
+	"GUID":  true,
+	"HTML":  true,
+	"HTTP":  true,
+	"HTTPS": true,
+	"ID":    true,
+	"IP":    true,
+	"JSON":  true,
+	"LHS":   true,
+	"OS":    true,
+	"QPS":   true,
+	"RAM":   true,
+	"RHS":   true,
+	"RPC":   true,
+	"SLA":   true,
+	"SMTP":  true,
+	"SQL":   true,
+	"SSH":   true,
+	"TCP":   true,
+	"TLS":   true,
+	"TTL":   true,
+	"UDP":   true,
+	"UI":    true,
+	"UID":   true,
+	"UUID":  true,
+	"URI":   true,
+	"URL":   true,
+	"UTF8":  true,
+	"VM":    true,
+	"XML":   true,
+	"XMPP":  true,
+	"XSRF":  true,
+	"XSS":   true,
+	"OAuth": true,
+}
+
+// athenaDirectives is the result of parsing an `athena:"..."` struct tag,
+// e.g. `athena:"partition"`, `athena:"partition,type=string"`, or
+// `athena:",skip"`.
+type athenaDirectives struct {
+	skip         bool
+	partition    bool
+	explicitType string
+}
+
+func parseAthenaTag(tag string, ok bool) athenaDirectives {
+	var directives athenaDirectives
+	if !ok {
+		return directives
+	}
+
+	for _, part := range splitAthenaTag(tag) {
+		switch {
+		case part == "":
+			continue
+		case part == "skip" || part == "-":
+			directives.skip = true
+		case part == "partition":
+			directives.partition = true
+		case strings.HasPrefix(part, "type="):
+			directives.explicitType = strings.TrimPrefix(part, "type=")
+		default:
+			directives.explicitType = part
+		}
+	}
+
+	return directives
+}
+
+// splitAthenaTag splits an `athena:"..."` tag on top-level commas, ignoring
+// commas inside parens so type overrides like "decimal(10,2)" survive intact.
+func splitAthenaTag(tag string) []string {
+	var parts []string
+	depth := 0
+	start := 0
+	for i, r := range tag {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			if depth > 0 {
+				depth--
+			}
+		case ',':
+			if depth == 0 {
+				parts = append(parts, tag[start:i])
+				start = i + 1
+			}
+		}
+	}
+	parts = append(parts, tag[start:])
+	return parts
+}
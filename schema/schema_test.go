@@ -0,0 +1,43 @@
+package schema
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFromType_ExplicitTypeOverrideWithParens(t *testing.T) {
+	type Post struct {
+		Price string `athena:"decimal(10,2)"`
+		Code  string `athena:"varchar(255)"`
+	}
+
+	table, err := FromType(reflect.TypeOf(Post{}), Options{})
+	if err != nil {
+		t.Fatalf("FromType: %v", err)
+	}
+
+	want := []Column{
+		{Name: "price", Type: "decimal(10,2)"},
+		{Name: "code", Type: "varchar(255)"},
+	}
+	if !reflect.DeepEqual(table.Columns, want) {
+		t.Errorf("Columns = %+v, want %+v", table.Columns, want)
+	}
+}
+
+func TestFromType_PartitionWithTypeOverride(t *testing.T) {
+	type Post struct {
+		Title string `json:"title"`
+		Count string `athena:"partition,type=string"`
+	}
+
+	table, err := FromType(reflect.TypeOf(Post{}), Options{})
+	if err != nil {
+		t.Fatalf("FromType: %v", err)
+	}
+
+	want := []Column{{Name: "count", Type: "string"}}
+	if !reflect.DeepEqual(table.PartitionColumns, want) {
+		t.Errorf("PartitionColumns = %+v, want %+v", table.PartitionColumns, want)
+	}
+}